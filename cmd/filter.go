@@ -0,0 +1,115 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/databus23/helm-diff/manifest"
+	"gopkg.in/yaml.v2"
+)
+
+// selectorOptions narrows a set of parsed manifests down to the resources a
+// user actually wants to see, as a filtering stage run between
+// manifest.Parse*/ParseRelease and diff.DiffManifests.
+type selectorOptions struct {
+	includeKinds []string
+	excludeKinds []string
+	selectors    []string
+	excludeNames []string
+}
+
+func (o selectorOptions) empty() bool {
+	return len(o.includeKinds) == 0 && len(o.excludeKinds) == 0 && len(o.selectors) == 0 && len(o.excludeNames) == 0
+}
+
+// resourceMeta is the subset of a resource's YAML we need to evaluate
+// label selectors; MappingResult only stores the raw per-resource content,
+// so we pull the fields we need out of it on demand.
+type resourceMeta struct {
+	Metadata struct {
+		Labels map[string]string `yaml:"labels"`
+	} `yaml:"metadata"`
+}
+
+// filterSpecs applies includeKinds/excludeKinds/selectors/excludeNames to
+// specs, returning a new map so the current and new spec sides can be
+// pruned symmetrically before diffing; when no filter is set, specs itself
+// is returned unchanged. It errors out rather than silently
+// mis-filtering on a malformed --selector or a resource it can't parse.
+func filterSpecs(specs map[string]*manifest.MappingResult, opts selectorOptions) (map[string]*manifest.MappingResult, error) {
+	if opts.empty() {
+		return specs, nil
+	}
+
+	filtered := make(map[string]*manifest.MappingResult, len(specs))
+	for key, entry := range specs {
+		if !matchesKindFilters(entry.Kind, opts) {
+			continue
+		}
+		if matchesExcludedName(entry.Name, opts.excludeNames) {
+			continue
+		}
+		matches, err := matchesSelectors(entry, opts.selectors)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", key, err)
+		}
+		if !matches {
+			continue
+		}
+		filtered[key] = entry
+	}
+	return filtered, nil
+}
+
+func matchesKindFilters(kind string, opts selectorOptions) bool {
+	if len(opts.includeKinds) > 0 && !containsFold(opts.includeKinds, kind) {
+		return false
+	}
+	if len(opts.excludeKinds) > 0 && containsFold(opts.excludeKinds, kind) {
+		return false
+	}
+	return true
+}
+
+func matchesExcludedName(name string, excludeNames []string) bool {
+	return containsFold(excludeNames, name)
+}
+
+func matchesSelectors(entry *manifest.MappingResult, selectors []string) (bool, error) {
+	if len(selectors) == 0 {
+		return true, nil
+	}
+
+	var meta resourceMeta
+	if err := yaml.Unmarshal([]byte(entry.Content), &meta); err != nil {
+		return false, fmt.Errorf("could not evaluate --selector against %s %q: %v", entry.Kind, entry.Name, err)
+	}
+
+	for _, selector := range selectors {
+		key, value, err := splitSelector(selector)
+		if err != nil {
+			return false, err
+		}
+		if meta.Metadata.Labels[key] != value {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+func splitSelector(selector string) (key, value string, err error) {
+	parts := strings.SplitN(selector, "=", 2)
+	if len(parts) != 2 {
+		return "", "", fmt.Errorf("invalid --selector %q, expected the form key=value", selector)
+	}
+	return strings.TrimSpace(parts[0]), strings.TrimSpace(parts[1]), nil
+}
+
+func containsFold(haystack []string, needle string) bool {
+	for _, candidate := range haystack {
+		if strings.EqualFold(candidate, needle) {
+			return true
+		}
+	}
+	return false
+}
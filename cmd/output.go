@@ -0,0 +1,53 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/databus23/helm-diff/diff"
+	"github.com/databus23/helm-diff/manifest"
+	"gopkg.in/yaml.v2"
+)
+
+// supportedOutputFormats lists the values accepted by the --output flag.
+var supportedOutputFormats = []string{"diff", "json", "yaml"}
+
+// renderDiff writes the diff between currentSpecs and newSpecs to w in the
+// requested format, returning whether any changes were found. For the
+// "diff" format this defers to diff.DiffManifests directly so its
+// plain-text unified-diff output is unchanged; "json" and "yaml" instead
+// render the structured result from diff.DiffManifestsStructured, making
+// the output safe for downstream tooling to parse.
+func renderDiff(currentSpecs, newSpecs map[string]*manifest.MappingResult, suppressedKinds []string, context int, format string, w io.Writer) (bool, error) {
+	switch format {
+	case "", "diff":
+		return diff.DiffManifests(currentSpecs, newSpecs, suppressedKinds, context, w), nil
+	case "json", "yaml":
+		resourceDiffs := diff.DiffManifestsStructured(currentSpecs, newSpecs, suppressedKinds, context)
+
+		var (
+			out []byte
+			err error
+		)
+		if format == "json" {
+			out, err = json.MarshalIndent(resourceDiffs, "", "  ")
+		} else {
+			out, err = yaml.Marshal(resourceDiffs)
+		}
+		if err != nil {
+			return false, err
+		}
+
+		if _, err := w.Write(out); err != nil {
+			return false, err
+		}
+		if format == "json" {
+			fmt.Fprintln(w)
+		}
+
+		return len(resourceDiffs) > 0, nil
+	default:
+		return false, fmt.Errorf("unsupported --output format %q, must be one of %v", format, supportedOutputFormats)
+	}
+}
@@ -0,0 +1,55 @@
+package cmd
+
+import (
+	"strings"
+
+	"k8s.io/helm/pkg/chartutil"
+	"k8s.io/helm/pkg/engine"
+	"k8s.io/helm/pkg/proto/hapi/chart"
+)
+
+// renderChartLocally renders chartPath with rawVals in-process using the
+// Helm template engine, without talking to Tiller. It is used by
+// --render-only to produce the "new" side of a diff from a chart the user
+// only has local (not cluster) access to. newInstall reflects whether this
+// is a fresh install or an upgrade of an existing release, so templates
+// gated on .Release.IsInstall/.Release.IsUpgrade render the same branch
+// the real operation would take.
+func renderChartLocally(chartPath string, rawVals []byte, releaseName, namespace string, newInstall bool) (string, error) {
+	chrt, err := chartutil.Load(chartPath)
+	if err != nil {
+		return "", err
+	}
+
+	renderValues, err := chartutil.ToRenderValuesCaps(chrt, &chart.Config{Raw: string(rawVals)}, chartutil.ReleaseOptions{
+		Name:      releaseName,
+		Namespace: namespace,
+		IsInstall: newInstall,
+		IsUpgrade: !newInstall,
+	}, &chartutil.DefaultCapabilities)
+	if err != nil {
+		return "", err
+	}
+
+	rendered, err := engine.New().Render(chrt, renderValues)
+	if err != nil {
+		return "", err
+	}
+
+	var manifestDoc strings.Builder
+	for name, content := range rendered {
+		if strings.TrimSpace(content) == "" {
+			continue
+		}
+		if !strings.HasSuffix(name, ".yaml") && !strings.HasSuffix(name, ".yml") {
+			continue
+		}
+		manifestDoc.WriteString("---\n# Source: ")
+		manifestDoc.WriteString(name)
+		manifestDoc.WriteString("\n")
+		manifestDoc.WriteString(content)
+		manifestDoc.WriteString("\n")
+	}
+
+	return manifestDoc.String(), nil
+}
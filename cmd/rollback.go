@@ -0,0 +1,175 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+
+	"errors"
+
+	"github.com/databus23/helm-diff/diff"
+	"github.com/databus23/helm-diff/manifest"
+	"github.com/spf13/cobra"
+	"k8s.io/helm/pkg/helm"
+	hapi_release "k8s.io/helm/pkg/proto/hapi/release"
+)
+
+type rollbackCmd struct {
+	release          string
+	revision         int
+	against          int
+	client           helm.Interface
+	detailedExitCode bool
+	suppressedKinds  []string
+	outputContext    int
+}
+
+const rollbackCmdLongUsage = `Show a diff explaining what a helm rollback would change.
+
+This fetches the currently deployed version of a release
+and compares it to a previous revision of the release, without
+actually performing the rollback. This can be used to check the
+consequences of a rollback before executing it.
+`
+
+func newRollbackCmd() *cobra.Command {
+	diff := rollbackCmd{}
+
+	cmd := &cobra.Command{
+		Use:     "rollback [flags] RELEASE [REVISION]",
+		Short:   "Show a diff explaining what a helm rollback would change.",
+		Long:    rollbackCmdLongUsage,
+		Example: "  helm diff rollback my-release 2",
+		Args: func(cmd *cobra.Command, args []string) error {
+			if len(args) < 1 || len(args) > 2 {
+				return fmt.Errorf("requires at least a release name, and optionally a target revision")
+			}
+			return nil
+		},
+		PersistentPreRun: func(*cobra.Command, []string) {
+			expandTLSPaths()
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.SilenceUsage = true
+
+			if q, _ := cmd.Flags().GetBool("suppress-secrets"); q {
+				diff.suppressedKinds = append(diff.suppressedKinds, "Secret")
+			}
+
+			diff.release = args[0]
+			if len(args) == 2 {
+				revision, err := strconv.Atoi(args[1])
+				if err != nil {
+					return fmt.Errorf("invalid revision %q: %v", args[1], err)
+				}
+				diff.revision = revision
+			}
+
+			if diff.client == nil {
+				diff.client = createHelmClient()
+			}
+			return diff.run()
+		},
+	}
+
+	f := cmd.Flags()
+	f.IntVar(&diff.revision, "revision", 0, "revision to rollback to. Defaults to the previous deployed revision when omitted")
+	f.IntVar(&diff.against, "against", 0, "revision to compare the rollback target against, instead of the currently deployed revision")
+	f.BoolVar(&diff.detailedExitCode, "detailed-exitcode", false, "return a non-zero exit code when there are changes")
+	f.BoolP("suppress-secrets", "q", false, "suppress secrets in the output")
+	f.StringArrayVar(&diff.suppressedKinds, "suppress", []string{}, "allows suppression of the values listed in the diff output")
+	f.IntVarP(&diff.outputContext, "context", "C", -1, "output NUM lines of context around changes")
+
+	addCommonCmdOptions(f)
+
+	return cmd
+}
+
+func (d *rollbackCmd) run() error {
+	history, err := d.client.ReleaseHistory(d.release)
+	if err != nil {
+		return prettyError(err)
+	}
+	if len(history.Releases) == 0 {
+		return fmt.Errorf("release %q has no history", d.release)
+	}
+
+	targetRevision := d.revision
+	if targetRevision == 0 {
+		targetRevision = previousDeployedRevision(history)
+		if targetRevision == 0 {
+			return fmt.Errorf("could not determine a previous deployed revision for release %q, specify one explicitly with --revision", d.release)
+		}
+	}
+
+	againstRevision := d.against
+	if againstRevision == 0 {
+		againstRevision = currentDeployedRevision(history)
+		if againstRevision == 0 {
+			return fmt.Errorf("could not determine the currently deployed revision for release %q, specify one explicitly with --against", d.release)
+		}
+	}
+
+	currentResponse, err := d.client.ReleaseContent(d.release, helm.ContentReleaseVersion(int32(againstRevision)))
+	if err != nil {
+		return prettyError(err)
+	}
+
+	targetResponse, err := d.client.ReleaseContent(d.release, helm.ContentReleaseVersion(int32(targetRevision)))
+	if err != nil {
+		return prettyError(err)
+	}
+
+	currentSpecs := manifest.ParseRelease(currentResponse.Release)
+	newSpecs := manifest.ParseRelease(targetResponse.Release)
+
+	seenAnyChanges := diff.DiffManifests(currentSpecs, newSpecs, d.suppressedKinds, d.outputContext, os.Stdout)
+
+	if d.detailedExitCode && seenAnyChanges {
+		return Error{
+			error: errors.New("identified at least one change, exiting with non-zero exit code (detailed-exitcode parameter enabled)"),
+			Code:  2,
+		}
+	}
+
+	return nil
+}
+
+// currentDeployedRevision returns the revision number of the release
+// history entry that is actually DEPLOYED right now, or 0 if none is.
+// Unlike the highest version number, this skips over a failed or
+// superseded top revision.
+func currentDeployedRevision(history *helm.ReleaseHistoryResponse) int {
+	deployed := 0
+	for _, rel := range history.Releases {
+		if rel.Info == nil || rel.Info.Status == nil || rel.Info.Status.Code != hapi_release.Status_DEPLOYED {
+			continue
+		}
+		if int(rel.Version) > deployed {
+			deployed = int(rel.Version)
+		}
+	}
+	return deployed
+}
+
+// previousDeployedRevision returns the revision number of the last release
+// before the current one whose status is DEPLOYED, or 0 if none can be
+// found. Failed or superseded revisions are skipped, matching what `helm
+// rollback` picks by default.
+func previousDeployedRevision(history *helm.ReleaseHistoryResponse) int {
+	best := 0
+	current := currentDeployedRevision(history)
+	for _, rel := range history.Releases {
+		revision := int(rel.Version)
+		if revision == current {
+			continue
+		}
+		if rel.Info == nil || rel.Info.Status == nil || rel.Info.Status.Code != hapi_release.Status_DEPLOYED {
+			continue
+		}
+		if revision > best {
+			best = revision
+		}
+	}
+	return best
+}
@@ -0,0 +1,88 @@
+package cmd
+
+import (
+	"os"
+
+	"errors"
+
+	"github.com/databus23/helm-diff/diff"
+	"github.com/databus23/helm-diff/manifest"
+	"github.com/spf13/cobra"
+	"k8s.io/helm/pkg/helm"
+)
+
+type uninstallCmd struct {
+	release          string
+	client           helm.Interface
+	detailedExitCode bool
+	suppressedKinds  []string
+	outputContext    int
+}
+
+const uninstallCmdLongUsage = `Show a diff explaining what a helm delete would change.
+
+This fetches the currently deployed version of a release
+and compares it against an empty release, so you can review
+everything that would be removed before running helm delete.
+`
+
+func newUninstallCmd() *cobra.Command {
+	diff := uninstallCmd{}
+
+	cmd := &cobra.Command{
+		Use:     "uninstall [flags] RELEASE",
+		Short:   "Show a diff explaining what a helm delete would change.",
+		Long:    uninstallCmdLongUsage,
+		Example: "  helm diff uninstall my-release",
+		Args: func(cmd *cobra.Command, args []string) error {
+			return checkArgsLength(len(args), "release name")
+		},
+		PersistentPreRun: func(*cobra.Command, []string) {
+			expandTLSPaths()
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.SilenceUsage = true
+
+			if q, _ := cmd.Flags().GetBool("suppress-secrets"); q {
+				diff.suppressedKinds = append(diff.suppressedKinds, "Secret")
+			}
+
+			diff.release = args[0]
+			if diff.client == nil {
+				diff.client = createHelmClient()
+			}
+			return diff.run()
+		},
+	}
+
+	f := cmd.Flags()
+	f.BoolVar(&diff.detailedExitCode, "detailed-exitcode", false, "return a non-zero exit code when there are changes")
+	f.BoolP("suppress-secrets", "q", false, "suppress secrets in the output")
+	f.StringArrayVar(&diff.suppressedKinds, "suppress", []string{}, "allows suppression of the values listed in the diff output")
+	f.IntVarP(&diff.outputContext, "context", "C", -1, "output NUM lines of context around changes")
+
+	addCommonCmdOptions(f)
+
+	return cmd
+}
+
+func (d *uninstallCmd) run() error {
+	releaseResponse, err := d.client.ReleaseContent(d.release)
+	if err != nil {
+		return prettyError(err)
+	}
+
+	currentSpecs := manifest.ParseRelease(releaseResponse.Release)
+	newSpecs := make(map[string]*manifest.MappingResult)
+
+	seenAnyChanges := diff.DiffManifests(currentSpecs, newSpecs, d.suppressedKinds, d.outputContext, os.Stdout)
+
+	if d.detailedExitCode && seenAnyChanges {
+		return Error{
+			error: errors.New("identified at least one change, exiting with non-zero exit code (detailed-exitcode parameter enabled)"),
+			Code:  2,
+		}
+	}
+
+	return nil
+}
@@ -29,6 +29,13 @@ type diffCmd struct {
 	allowUnreleased  bool
 	suppressedKinds  []string
 	outputContext    int
+	hideSecret       bool
+	output           string
+	renderOnly       bool
+	includeKinds     []string
+	excludeKinds     []string
+	selectors        []string
+	excludeNames     []string
 }
 
 const globalUsage = `Show a diff explaining what a helm upgrade would change.
@@ -84,6 +91,14 @@ func newChartCommand() *cobra.Command {
 	f.StringArrayVar(&diff.suppressedKinds, "suppress", []string{}, "allows suppression of the values listed in the diff output")
 	f.IntVarP(&diff.outputContext, "context", "C", -1, "output NUM lines of context around changes")
 	f.StringVar(&diff.namespace, "namespace", "default", "namespace to assume the release to be installed into")
+	f.BoolVar(&diff.hideSecret, "hide-secret", false, "hide Secret's data values in the diff output, keeping the resource visible but redacting its contents")
+	f.StringVar(&diff.output, "output", "diff", "output format, one of: diff, json, yaml")
+	f.BoolVar(&diff.renderOnly, "render-only", false, "render the new manifest locally via the chart's templates instead of a Tiller dry-run, for clusters where only read access to Tiller is available")
+	f.BoolVar(&diff.renderOnly, "no-tiller", false, "alias of --render-only")
+	f.StringArrayVar(&diff.includeKinds, "include-kind", []string{}, "only show resources of the given kind in the diff (can specify multiple)")
+	f.StringArrayVar(&diff.excludeKinds, "exclude-kind", []string{}, "hide resources of the given kind from the diff (can specify multiple)")
+	f.StringArrayVar(&diff.selectors, "selector", []string{}, "only show resources matching the given label, e.g. app=foo (can specify multiple)")
+	f.StringArrayVar(&diff.excludeNames, "exclude-name", []string{}, "hide the resource with the given name from the diff (can specify multiple)")
 
 	addCommonCmdOptions(f)
 
@@ -129,7 +144,24 @@ func (d *diffCmd) run() error {
 	}
 
 	var currentSpecs, newSpecs map[string]*manifest.MappingResult
-	if newInstall {
+	if d.renderOnly {
+		renderNamespace := d.namespace
+		if !newInstall {
+			renderNamespace = releaseResponse.Release.Namespace
+		}
+
+		renderedManifest, err := renderChartLocally(chartPath, rawVals, d.release, renderNamespace, newInstall)
+		if err != nil {
+			return prettyError(err)
+		}
+
+		if newInstall {
+			currentSpecs = make(map[string]*manifest.MappingResult)
+		} else {
+			currentSpecs = manifest.ParseRelease(releaseResponse.Release)
+		}
+		newSpecs = manifest.Parse(renderedManifest, renderNamespace)
+	} else if newInstall {
 		installResponse, err := d.client.InstallRelease(
 			chartPath,
 			d.namespace,
@@ -160,7 +192,30 @@ func (d *diffCmd) run() error {
 		newSpecs = manifest.ParseRelease(upgradeResponse.Release)
 	}
 
-	seenAnyChanges := diff.DiffManifests(currentSpecs, newSpecs, d.suppressedKinds, d.outputContext, os.Stdout)
+	if d.hideSecret {
+		manifest.RedactSecrets(currentSpecs)
+		manifest.RedactSecrets(newSpecs)
+	}
+
+	selectors := selectorOptions{
+		includeKinds: d.includeKinds,
+		excludeKinds: d.excludeKinds,
+		selectors:    d.selectors,
+		excludeNames: d.excludeNames,
+	}
+	currentSpecs, err = filterSpecs(currentSpecs, selectors)
+	if err != nil {
+		return err
+	}
+	newSpecs, err = filterSpecs(newSpecs, selectors)
+	if err != nil {
+		return err
+	}
+
+	seenAnyChanges, err := renderDiff(currentSpecs, newSpecs, d.suppressedKinds, d.outputContext, d.output, os.Stdout)
+	if err != nil {
+		return err
+	}
 
 	if d.detailedExitCode && seenAnyChanges {
 		return Error{
@@ -0,0 +1,209 @@
+// Package diff compares the parsed manifests of two releases, resource by
+// resource, and renders the result either as a textual unified diff or as
+// a structured value for machine consumption.
+package diff
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/aryann/difflib"
+	"github.com/databus23/helm-diff/manifest"
+	"gopkg.in/yaml.v2"
+)
+
+// ChangeType describes how a resource differs between the current and new
+// spec.
+type ChangeType string
+
+const (
+	Added    ChangeType = "added"
+	Removed  ChangeType = "removed"
+	Modified ChangeType = "modified"
+)
+
+// ResourceDiff is the structured, machine-readable form of a single
+// resource's change, used by the --output json/yaml renderers.
+type ResourceDiff struct {
+	APIVersion string     `json:"apiVersion,omitempty" yaml:"apiVersion,omitempty"`
+	Kind       string     `json:"kind" yaml:"kind"`
+	Namespace  string     `json:"namespace,omitempty" yaml:"namespace,omitempty"`
+	Name       string     `json:"name" yaml:"name"`
+	ChangeType ChangeType `json:"changeType" yaml:"changeType"`
+	Hunks      []string   `json:"hunks" yaml:"hunks"`
+}
+
+type resourceIdentity struct {
+	APIVersion string `yaml:"apiVersion"`
+	Metadata   struct {
+		Namespace string `yaml:"namespace"`
+	} `yaml:"metadata"`
+}
+
+// DiffManifests writes a plain-text unified diff (no color codes) of every
+// resource that changed between currentSpecs and newSpecs to to, and
+// returns whether any changes were found.
+func DiffManifests(currentSpecs, newSpecs map[string]*manifest.MappingResult, suppressedKinds []string, context int, to io.Writer) bool {
+	seenAnyChanges := false
+
+	for _, key := range sortedKeys(currentSpecs, newSpecs) {
+		current, new_ := currentSpecs[key], newSpecs[key]
+		if isSuppressed(current, new_, suppressedKinds) {
+			continue
+		}
+
+		fromContent, toContent := contentOf(current), contentOf(new_)
+		if fromContent == toContent {
+			continue
+		}
+
+		seenAnyChanges = true
+		fmt.Fprintf(to, "%s, %s has changed:\n", key, kindOf(current, new_))
+		printUnifiedDiff(fromContent, toContent, context, to)
+	}
+
+	return seenAnyChanges
+}
+
+// DiffManifestsStructured mirrors DiffManifests but returns a structured
+// []ResourceDiff instead of writing text, for consumption by the --output
+// json/yaml renderers.
+func DiffManifestsStructured(currentSpecs, newSpecs map[string]*manifest.MappingResult, suppressedKinds []string, context int) []ResourceDiff {
+	var result []ResourceDiff
+
+	for _, key := range sortedKeys(currentSpecs, newSpecs) {
+		current, new_ := currentSpecs[key], newSpecs[key]
+		if isSuppressed(current, new_, suppressedKinds) {
+			continue
+		}
+
+		fromContent, toContent := contentOf(current), contentOf(new_)
+		if fromContent == toContent {
+			continue
+		}
+
+		changeType := Modified
+		switch {
+		case current == nil:
+			changeType = Added
+		case new_ == nil:
+			changeType = Removed
+		}
+
+		entry := new_
+		if entry == nil {
+			entry = current
+		}
+
+		result = append(result, ResourceDiff{
+			APIVersion: apiVersionOf(entry),
+			Kind:       kindOf(current, new_),
+			Namespace:  namespaceOf(key),
+			Name:       entry.Name,
+			ChangeType: changeType,
+			Hunks:      unifiedDiffLines(fromContent, toContent, context),
+		})
+	}
+
+	return result
+}
+
+func sortedKeys(currentSpecs, newSpecs map[string]*manifest.MappingResult) []string {
+	seen := make(map[string]struct{}, len(currentSpecs)+len(newSpecs))
+	for key := range currentSpecs {
+		seen[key] = struct{}{}
+	}
+	for key := range newSpecs {
+		seen[key] = struct{}{}
+	}
+
+	keys := make([]string, 0, len(seen))
+	for key := range seen {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func isSuppressed(current, new_ *manifest.MappingResult, suppressedKinds []string) bool {
+	kind := kindOf(current, new_)
+	for _, suppressed := range suppressedKinds {
+		if strings.EqualFold(suppressed, kind) {
+			return true
+		}
+	}
+	return false
+}
+
+func contentOf(entry *manifest.MappingResult) string {
+	if entry == nil {
+		return ""
+	}
+	return entry.Content
+}
+
+func kindOf(current, new_ *manifest.MappingResult) string {
+	if new_ != nil {
+		return new_.Kind
+	}
+	if current != nil {
+		return current.Kind
+	}
+	return ""
+}
+
+func apiVersionOf(entry *manifest.MappingResult) string {
+	if entry == nil {
+		return ""
+	}
+	var identity resourceIdentity
+	if err := yaml.Unmarshal([]byte(entry.Content), &identity); err != nil {
+		return ""
+	}
+	return identity.APIVersion
+}
+
+func namespaceOf(key string) string {
+	namespace := strings.SplitN(key, "/", 2)[0]
+	return namespace
+}
+
+func printUnifiedDiff(from, to string, context int, w io.Writer) {
+	for _, line := range unifiedDiffLines(from, to, context) {
+		fmt.Fprintln(w, line)
+	}
+}
+
+func unifiedDiffLines(from, to string, context int) []string {
+	diffRecords := difflib.Diff(strings.Split(from, "\n"), strings.Split(to, "\n"))
+
+	lines := make([]string, 0, len(diffRecords))
+	for i, record := range diffRecords {
+		switch record.Delta {
+		case difflib.LeftOnly:
+			lines = append(lines, "- "+record.Payload)
+		case difflib.RightOnly:
+			lines = append(lines, "+ "+record.Payload)
+		case difflib.Common:
+			if context < 0 {
+				continue
+			}
+			lo, hi := i-context, i+context
+			if lo < 0 {
+				lo = 0
+			}
+			if hi >= len(diffRecords) {
+				hi = len(diffRecords) - 1
+			}
+			for j := lo; j <= hi; j++ {
+				if diffRecords[j].Delta != difflib.Common {
+					lines = append(lines, "  "+record.Payload)
+					break
+				}
+			}
+		}
+	}
+	return lines
+}
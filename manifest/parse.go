@@ -0,0 +1,63 @@
+// Package manifest turns the raw, multi-document YAML manifest of a Helm
+// release into individually addressable resources so the diff package can
+// compare them one at a time.
+package manifest
+
+import (
+	"strings"
+
+	"gopkg.in/yaml.v2"
+	"k8s.io/helm/pkg/proto/hapi/release"
+)
+
+// MappingResult is a single Kubernetes resource extracted from a release
+// manifest, keyed elsewhere by its namespace/kind/name.
+type MappingResult struct {
+	Name    string
+	Kind    string
+	Content string
+}
+
+type resourceHeader struct {
+	Kind     string `yaml:"kind"`
+	Metadata struct {
+		Name      string `yaml:"name"`
+		Namespace string `yaml:"namespace"`
+	} `yaml:"metadata"`
+}
+
+// Parse splits manifestString into its constituent "---"-separated
+// documents and returns them keyed by "namespace/kind/name".
+func Parse(manifestString, defaultNamespace string) map[string]*MappingResult {
+	result := make(map[string]*MappingResult)
+
+	for _, doc := range strings.Split(manifestString, "\n---") {
+		if strings.TrimSpace(doc) == "" {
+			continue
+		}
+
+		var header resourceHeader
+		if err := yaml.Unmarshal([]byte(doc), &header); err != nil || header.Kind == "" {
+			continue
+		}
+
+		namespace := header.Metadata.Namespace
+		if namespace == "" {
+			namespace = defaultNamespace
+		}
+
+		key := namespace + "/" + header.Kind + "/" + header.Metadata.Name
+		result[key] = &MappingResult{
+			Name:    header.Metadata.Name,
+			Kind:    header.Kind,
+			Content: doc,
+		}
+	}
+
+	return result
+}
+
+// ParseRelease parses the manifest of a deployed release.
+func ParseRelease(rel *release.Release) map[string]*MappingResult {
+	return Parse(rel.Manifest, rel.Namespace)
+}
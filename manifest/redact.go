@@ -0,0 +1,56 @@
+package manifest
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+
+	"gopkg.in/yaml.v2"
+)
+
+// RedactSecrets scrubs the data/stringData values of every Secret in specs
+// in place, replacing each value with a stable hash of its original
+// content. The diff still shows whether a key was added, removed or
+// changed, without leaking what it actually held.
+func RedactSecrets(specs map[string]*MappingResult) {
+	for _, entry := range specs {
+		if entry.Kind != "Secret" {
+			continue
+		}
+
+		var doc map[string]interface{}
+		if err := yaml.Unmarshal([]byte(entry.Content), &doc); err != nil {
+			continue
+		}
+
+		redactField(doc, "data")
+		redactField(doc, "stringData")
+
+		redacted, err := yaml.Marshal(doc)
+		if err != nil {
+			continue
+		}
+		entry.Content = string(redacted)
+	}
+}
+
+func redactField(doc map[string]interface{}, field string) {
+	raw, ok := doc[field]
+	if !ok {
+		return
+	}
+
+	values, ok := raw.(map[interface{}]interface{})
+	if !ok {
+		return
+	}
+
+	for key, value := range values {
+		values[key] = redactValue(fmt.Sprintf("%v", value))
+	}
+}
+
+func redactValue(value string) string {
+	sum := sha256.Sum256([]byte(value))
+	return "REDACTED-" + hex.EncodeToString(sum[:])[:12]
+}